@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/NasoohOlabi/stego-side-wing/config"
+	"github.com/NasoohOlabi/stego-side-wing/manifest"
+	"github.com/NasoohOlabi/stego-side-wing/storage"
+	"github.com/NasoohOlabi/stego-side-wing/telemetry"
+)
+
+// dataset pairs a policy's input/output directories with the Storage
+// backend each one resolves to, so the rest of the pipeline never touches
+// os/filepath directly and can run against local disk, an in-memory
+// filesystem, or an S3 bucket interchangeably.
+type dataset struct {
+	Input     storage.Storage
+	InputDir  string
+	Output    storage.Storage
+	OutputDir string
+}
+
+func resolveDataset(policy config.Policy) (dataset, error) {
+	inputFS, inputDir, err := storage.New(policy.InputDir)
+	if err != nil {
+		return dataset{}, err
+	}
+	outputFS, outputDir, err := storage.New(policy.OutputDir)
+	if err != nil {
+		return dataset{}, err
+	}
+	return dataset{Input: inputFS, InputDir: inputDir, Output: outputFS, OutputDir: outputDir}, nil
+}
+
+func hashStorageFile(fs storage.Storage, path string) (string, error) {
+	r, err := fs.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	return manifest.Hash(r)
+}
+
+// hashStorageFileCounted is hashStorageFile plus the number of bytes read,
+// for callers that report it as telemetry.
+func hashStorageFileCounted(fs storage.Storage, path string) (hash string, bytesRead int64, err error) {
+	r, err := fs.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer r.Close()
+
+	counting := telemetry.NewCountingReader(r)
+	hash, err = manifest.Hash(counting)
+	return hash, counting.Bytes(), err
+}
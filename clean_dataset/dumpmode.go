@@ -0,0 +1,286 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/NasoohOlabi/stego-side-wing/config"
+	"github.com/NasoohOlabi/stego-side-wing/storage"
+	"github.com/NasoohOlabi/stego-side-wing/telemetry"
+)
+
+// runDumpMode ingests a Pushshift-style ndjson dump (one JSON post per
+// line, compressed with zstd or gzip), running the same allowlist/blocklist
+// /sanitize pipeline as runFileMode over every record. Records are sharded
+// across a fixed worker pool via a buffered channel; malformed lines are
+// skipped and counted rather than aborting the whole dump. Workers stop
+// picking up new lines once ctx is cancelled, letting in-flight records
+// finish. Both dumpPath and policy.OutputDir are resolved through the
+// Storage abstraction, so a dump (or its output) can live on local disk, in
+// memory, or in an S3 bucket, same as file mode.
+//
+// Every record's outcome is logged through logger and folded into a
+// telemetry.Reporter, same as file mode; the reporter's total is unknown
+// ahead of time since the dump is read as a stream, so progress is reported
+// without an ETA.
+func runDumpMode(ctx context.Context, dumpPath string, policy config.Policy, outputFormat string, logger *slog.Logger, metricsAddr string) error {
+	outputFS, outputDir, err := storage.New(policy.OutputDir)
+	if err != nil {
+		return err
+	}
+	if err := outputFS.MkdirAll(outputDir); err != nil {
+		return err
+	}
+
+	dumpFS, resolvedDumpPath, err := storage.New(dumpPath)
+	if err != nil {
+		return err
+	}
+
+	in, err := openDumpReader(dumpFS, resolvedDumpPath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	concurrencyLimit := policy.Concurrency
+	if concurrencyLimit <= 0 {
+		concurrencyLimit = runtime.NumCPU() * 2
+	}
+
+	reporter := telemetry.NewReporter(logger, 0)
+	reporterCtx, stopReporter := context.WithCancel(context.Background())
+	defer stopReporter()
+	go reporter.Run(reporterCtx, 5*time.Second)
+	if metricsAddr != "" {
+		go func() {
+			if err := reporter.ServeMetrics(metricsAddr); err != nil {
+				logger.Error("metrics server stopped", "error", err.Error())
+			}
+		}()
+	}
+
+	lines := make(chan string, concurrencyLimit*4)
+	var results chan map[string]interface{}
+	var writerWG sync.WaitGroup
+	if outputFormat == "ndjson-zst" {
+		results = make(chan map[string]interface{}, concurrencyLimit*4)
+		writerWG.Add(1)
+		go func() {
+			defer writerWG.Done()
+			outPath := storage.Join(outputDir, "cleaned.ndjson.zst")
+			start := time.Now()
+			bytesOut, err := writeNdjsonZst(outputFS, outPath, results)
+			reporter.Submit(telemetry.Event{File: outPath, Stage: "ndjson_zst_write", Duration: time.Since(start), BytesOut: bytesOut, Err: err})
+		}()
+	}
+
+	var malformed, kept, anonSeq int64
+	var workers sync.WaitGroup
+	for i := 0; i < concurrencyLimit; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for line := range lines {
+				recordStart := time.Now()
+
+				var post map[string]interface{}
+				if err := json.NewDecoder(strings.NewReader(line)).Decode(&post); err != nil {
+					atomic.AddInt64(&malformed, 1)
+					reporter.Submit(telemetry.Event{
+						Stage:         "decode",
+						Duration:      time.Since(recordStart),
+						BytesIn:       int64(len(line)),
+						SkippedReason: "malformed",
+						Err:           err,
+					})
+					continue
+				}
+
+				cleaned, skip, err := cleanPost(ctx, post, policy)
+				if err != nil {
+					reporter.Submit(telemetry.Event{
+						Stage:         "clean",
+						Duration:      time.Since(recordStart),
+						BytesIn:       int64(len(line)),
+						SkippedReason: "cancelled",
+						Err:           err,
+					})
+					continue
+				}
+				if skip {
+					reporter.Submit(telemetry.Event{
+						Stage:         "clean",
+						Duration:      time.Since(recordStart),
+						BytesIn:       int64(len(line)),
+						SkippedReason: "empty_comments",
+					})
+					continue
+				}
+				atomic.AddInt64(&kept, 1)
+
+				event := telemetry.Event{Stage: "clean", BytesIn: int64(len(line)), PostsKept: 1}
+				if comments, ok := cleaned["comments"].([]interface{}); ok {
+					event.CommentsKept = countComments(comments)
+				}
+
+				if outputFormat == "ndjson-zst" {
+					results <- cleaned
+				} else if bytesOut, err := writePostFile(outputFS, cleaned, outputDir, &anonSeq); err != nil {
+					event.Err = err
+				} else {
+					event.BytesOut = bytesOut
+				}
+				event.Duration = time.Since(recordStart)
+				reporter.Submit(event)
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines <- line
+	}
+	close(lines)
+	scanErr := scanner.Err()
+
+	workers.Wait()
+	if outputFormat == "ndjson-zst" {
+		close(results)
+		writerWG.Wait()
+	}
+	reporter.Close()
+
+	if scanErr != nil {
+		return scanErr
+	}
+
+	logger.Info("dump ingest complete", "kept", kept, "malformed", malformed)
+	return nil
+}
+
+// openDumpReader opens path on fs and wraps it with a zstd or gzip
+// decompressor based on its extension; any other extension is read raw.
+func openDumpReader(fs storage.Storage, path string) (io.ReadCloser, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".zst":
+		zr, err := zstd.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &zstdReadCloser{Decoder: zr, file: f}, nil
+	case ".gz":
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &gzipReadCloser{Reader: gr, file: f}, nil
+	default:
+		return f, nil
+	}
+}
+
+type zstdReadCloser struct {
+	*zstd.Decoder
+	file io.ReadCloser
+}
+
+func (z *zstdReadCloser) Close() error {
+	z.Decoder.Close()
+	return z.file.Close()
+}
+
+type gzipReadCloser struct {
+	*gzip.Reader
+	file io.ReadCloser
+}
+
+func (g *gzipReadCloser) Close() error {
+	if err := g.Reader.Close(); err != nil {
+		g.file.Close()
+		return err
+	}
+	return g.file.Close()
+}
+
+// writePostFile writes a single cleaned post as its own JSON file, named by
+// its Reddit "id" field, returning the number of bytes written. Records
+// without an "id" (or with a duplicate one) would otherwise collide and
+// silently clobber each other under concurrent workers, so a missing id
+// falls back to a name built from anonSeq, an atomic counter shared across
+// the worker pool, guaranteeing uniqueness.
+func writePostFile(fs storage.Storage, post map[string]interface{}, outputDir string, anonSeq *int64) (int64, error) {
+	id, _ := post["id"].(string)
+	if id == "" {
+		id = fmt.Sprintf("anon-%d", atomic.AddInt64(anonSeq, 1))
+	}
+
+	outFile, err := fs.Create(storage.Join(outputDir, id+".json"))
+	if err != nil {
+		return 0, err
+	}
+	defer outFile.Close()
+
+	counting := telemetry.NewCountingWriter(outFile)
+	encoder := json.NewEncoder(counting)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(post); err != nil {
+		return counting.Bytes(), err
+	}
+	return counting.Bytes(), nil
+}
+
+// writeNdjsonZst drains results, writing each post as one zstd-compressed
+// ndjson line to path on fs, returning the number of compressed bytes
+// written.
+func writeNdjsonZst(fs storage.Storage, path string, results <-chan map[string]interface{}) (int64, error) {
+	outFile, err := fs.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer outFile.Close()
+
+	counting := telemetry.NewCountingWriter(outFile)
+	zw, err := zstd.NewWriter(counting)
+	if err != nil {
+		return 0, err
+	}
+	defer zw.Close()
+
+	encoder := json.NewEncoder(zw)
+	var encodeErr error
+	for post := range results {
+		if err := encoder.Encode(post); err != nil && encodeErr == nil {
+			encodeErr = err
+		}
+	}
+	return counting.Bytes(), encodeErr
+}
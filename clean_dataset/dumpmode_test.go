@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/NasoohOlabi/stego-side-wing/telemetry"
+)
+
+func testLogger(t *testing.T) *slog.Logger {
+	t.Helper()
+	logger, err := telemetry.NewLogger("error", "text")
+	if err != nil {
+		t.Fatalf("telemetry.NewLogger: %v", err)
+	}
+	return logger
+}
+
+func writeZstDump(t *testing.T, path string, lines []string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create dump: %v", err)
+	}
+	defer f.Close()
+
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		t.Fatalf("zstd.NewWriter: %v", err)
+	}
+	defer zw.Close()
+
+	for _, line := range lines {
+		if _, err := zw.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("write line: %v", err)
+		}
+	}
+}
+
+func writeGzipDump(t *testing.T, path string, lines []string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create dump: %v", err)
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	defer gw.Close()
+
+	for _, line := range lines {
+		if _, err := gw.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("write line: %v", err)
+		}
+	}
+}
+
+// TestRunDumpModeFilesNoIDCollision reproduces a dump where multiple
+// records lack an "id": every record must still land as its own file
+// instead of clobbering a shared "post.json".
+func TestRunDumpModeFilesNoIDCollision(t *testing.T) {
+	dir := t.TempDir()
+	dumpPath := filepath.Join(dir, "dump.ndjson.zst")
+	outputDir := filepath.Join(dir, "out")
+
+	lines := []string{
+		mustJSON(t, map[string]interface{}{"id": "abc", "title": "has id", "comments": []interface{}{map[string]interface{}{"body": "hi"}}}),
+		mustJSON(t, map[string]interface{}{"title": "no id one", "comments": []interface{}{map[string]interface{}{"body": "hi"}}}),
+		mustJSON(t, map[string]interface{}{"title": "no id two", "comments": []interface{}{map[string]interface{}{"body": "hi"}}}),
+	}
+	writeZstDump(t, dumpPath, lines)
+
+	policy := testPolicy()
+	policy.OutputDir = outputDir
+	policy.Concurrency = 2
+
+	if err := runDumpMode(context.Background(), dumpPath, policy, "files", testLogger(t), ""); err != nil {
+		t.Fatalf("runDumpMode: %v", err)
+	}
+
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("got %d output files, want 3 (one per record, none clobbered): %v", len(entries), entries)
+	}
+}
+
+// TestRunDumpModeMalformedLinesCounted ensures malformed lines are skipped
+// rather than aborting the whole dump, and that well-formed records around
+// them still get processed.
+func TestRunDumpModeMalformedLinesCounted(t *testing.T) {
+	dir := t.TempDir()
+	dumpPath := filepath.Join(dir, "dump.ndjson.gz")
+	outputDir := filepath.Join(dir, "out")
+
+	lines := []string{
+		mustJSON(t, map[string]interface{}{"id": "good", "title": "ok", "comments": []interface{}{map[string]interface{}{"body": "hi"}}}),
+		"{not valid json",
+	}
+	writeGzipDump(t, dumpPath, lines)
+
+	policy := testPolicy()
+	policy.OutputDir = outputDir
+	policy.PostAllow["id"] = struct{}{}
+
+	if err := runDumpMode(context.Background(), dumpPath, policy, "files", testLogger(t), ""); err != nil {
+		t.Fatalf("runDumpMode: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outputDir, "good.json")); err != nil {
+		t.Errorf("good.json should have been written: %v", err)
+	}
+}
+
+// TestRunDumpModeNdjsonZstOutput exercises the ndjson-zst output path end to
+// end: the written file must be readable back as zstd-compressed ndjson.
+func TestRunDumpModeNdjsonZstOutput(t *testing.T) {
+	dir := t.TempDir()
+	dumpPath := filepath.Join(dir, "dump.ndjson.zst")
+	outputDir := filepath.Join(dir, "out")
+
+	lines := []string{
+		mustJSON(t, map[string]interface{}{"id": "a", "title": "one", "comments": []interface{}{map[string]interface{}{"body": "hi"}}}),
+		mustJSON(t, map[string]interface{}{"id": "b", "title": "two", "comments": []interface{}{map[string]interface{}{"body": "hi"}}}),
+	}
+	writeZstDump(t, dumpPath, lines)
+
+	policy := testPolicy()
+	policy.OutputDir = outputDir
+
+	if err := runDumpMode(context.Background(), dumpPath, policy, "ndjson-zst", testLogger(t), ""); err != nil {
+		t.Fatalf("runDumpMode: %v", err)
+	}
+
+	f, err := os.Open(filepath.Join(outputDir, "cleaned.ndjson.zst"))
+	if err != nil {
+		t.Fatalf("open output: %v", err)
+	}
+	defer f.Close()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		t.Fatalf("zstd.NewReader: %v", err)
+	}
+	defer zr.Close()
+
+	scanner := bufio.NewScanner(zr)
+	var count int
+	for scanner.Scan() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("got %d cleaned records in output, want 2", count)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NasoohOlabi/stego-side-wing/config"
+	"github.com/NasoohOlabi/stego-side-wing/manifest"
+	"github.com/NasoohOlabi/stego-side-wing/mdtemplate"
+	"github.com/NasoohOlabi/stego-side-wing/storage"
+	"github.com/NasoohOlabi/stego-side-wing/telemetry"
+)
+
+// outputOptions controls what processFile writes for each cleaned post.
+type outputOptions struct {
+	Mode      string // "json", "md", or "both"
+	Templates mdtemplate.Set
+}
+
+// fileResult carries the bookkeeping processFile needs to report back to
+// its caller: where the primary artifact landed, and the telemetry counters
+// for that one file.
+type fileResult struct {
+	OutputPath    string
+	BytesIn       int64
+	BytesOut      int64
+	PostsKept     int
+	CommentsKept  int
+	SkippedReason string
+}
+
+// runFileMode processes ds.InputDir as one JSON post per file, writing each
+// cleaned post into ds.OutputDir per out.Mode. ds.Input/ds.Output may be any
+// Storage backend (local disk, in-memory, S3), so the pipeline never
+// touches os/filepath directly.
+//
+// Every input file is hashed and checked against mf before reprocessing;
+// unless force is set, files whose hash is already recorded are skipped.
+// Workers stop picking up new files once ctx is cancelled but let any
+// in-flight file finish and record its manifest entry, so a re-run resumes
+// exactly where this one stopped; the manifest is flushed to disk before
+// runFileMode returns either way. Every file's outcome is logged through
+// logger and folded into a telemetry.Reporter, which prints live progress
+// and, when metricsAddr is non-empty, serves it as Prometheus metrics.
+func runFileMode(ctx context.Context, ds dataset, policy config.Policy, out outputOptions, mf *manifest.Manifest, force bool, logger *slog.Logger, metricsAddr string) error {
+	if err := ds.Output.MkdirAll(ds.OutputDir); err != nil {
+		return err
+	}
+
+	files, err := ds.Input.ReadDir(ds.InputDir)
+	if err != nil {
+		return err
+	}
+
+	concurrencyLimit := policy.Concurrency
+	if concurrencyLimit <= 0 {
+		concurrencyLimit = runtime.NumCPU() * 2
+	}
+	sem := make(chan struct{}, concurrencyLimit)
+	var wg sync.WaitGroup
+	var skippedUnchanged int64
+
+	reporter := telemetry.NewReporter(logger, len(files))
+	reporterCtx, stopReporter := context.WithCancel(context.Background())
+	defer stopReporter()
+	go reporter.Run(reporterCtx, 5*time.Second)
+	if metricsAddr != "" {
+		go func() {
+			if err := reporter.ServeMetrics(metricsAddr); err != nil {
+				logger.Error("metrics server stopped", "error", err.Error())
+			}
+		}()
+	}
+
+	for _, name := range files {
+		if filepath.Ext(name) != ".json" {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		inputPath := storage.Join(ds.InputDir, name)
+		hash, err := hashStorageFile(ds.Input, inputPath)
+		if err != nil {
+			reporter.Submit(telemetry.Event{File: name, Stage: "hash", Err: err})
+			continue
+		}
+		if !force && mf.Unchanged(name, hash) {
+			atomic.AddInt64(&skippedUnchanged, 1)
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(filename, inputHash string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fileStart := time.Now()
+			result, err := processFile(ctx, ds, policy, filename, out)
+			reporter.Submit(telemetry.Event{
+				File:          filename,
+				Stage:         "clean",
+				Duration:      time.Since(fileStart),
+				BytesIn:       result.BytesIn,
+				BytesOut:      result.BytesOut,
+				PostsKept:     result.PostsKept,
+				CommentsKept:  result.CommentsKept,
+				SkippedReason: result.SkippedReason,
+				Err:           err,
+			})
+			if err != nil {
+				return
+			}
+
+			entry := manifest.Entry{
+				InputSHA256: inputHash,
+				ModTime:     time.Now(),
+				OutputPath:  result.OutputPath,
+				LastSuccess: time.Now(),
+			}
+			if result.OutputPath != "" {
+				if outputHash, err := hashStorageFile(ds.Output, result.OutputPath); err == nil {
+					entry.OutputSHA256 = outputHash
+				}
+			}
+			mf.Record(filename, entry)
+		}(name, hash)
+	}
+
+	wg.Wait()
+	reporter.Close()
+	if err := mf.Save(); err != nil {
+		return fmt.Errorf("saving manifest: %w", err)
+	}
+
+	logger.Info("run complete", "skipped_unchanged", skippedUnchanged)
+	return nil
+}
+
+// processFile cleans one input post and writes it per out.Mode, returning
+// the path of its primary artifact (the .json if produced, else the .md)
+// plus byte/comment counters for telemetry.
+func processFile(ctx context.Context, ds dataset, policy config.Policy, filename string, out outputOptions) (fileResult, error) {
+	if ctx.Err() != nil {
+		return fileResult{}, ctx.Err()
+	}
+
+	inFile, err := ds.Input.Open(storage.Join(ds.InputDir, filename))
+	if err != nil {
+		return fileResult{}, fmt.Errorf("opening: %w", err)
+	}
+	defer inFile.Close()
+
+	counting := telemetry.NewCountingReader(inFile)
+	var post map[string]interface{}
+	decoder := json.NewDecoder(counting)
+	if err := decoder.Decode(&post); err != nil {
+		return fileResult{BytesIn: counting.Bytes()}, fmt.Errorf("decoding: %w", err)
+	}
+
+	cleaned, skip, err := cleanPost(ctx, post, policy)
+	if err != nil {
+		return fileResult{BytesIn: counting.Bytes()}, fmt.Errorf("cleaning: %w", err)
+	}
+	if skip {
+		return fileResult{BytesIn: counting.Bytes(), SkippedReason: "empty_comments"}, nil
+	}
+
+	result := fileResult{BytesIn: counting.Bytes(), PostsKept: 1}
+	if comments, ok := cleaned["comments"].([]interface{}); ok {
+		result.CommentsKept = countComments(comments)
+	}
+
+	stem := strings.TrimSuffix(filename, filepath.Ext(filename))
+
+	if out.Mode == "json" || out.Mode == "both" {
+		jsonPath := storage.Join(ds.OutputDir, stem+".json")
+		n, err := writeJSONPost(ds.Output, cleaned, jsonPath)
+		if err != nil {
+			return result, fmt.Errorf("writing json: %w", err)
+		}
+		result.BytesOut += n
+		result.OutputPath = jsonPath
+	}
+	if out.Mode == "md" || out.Mode == "both" {
+		mdPath := storage.Join(ds.OutputDir, stem+".md")
+		n, err := writeMarkdownThread(ctx, ds.Output, cleaned, mdPath, out.Templates)
+		if err != nil {
+			return result, fmt.Errorf("rendering markdown: %w", err)
+		}
+		result.BytesOut += n
+		if result.OutputPath == "" {
+			result.OutputPath = mdPath
+		}
+	}
+	return result, nil
+}
+
+// countComments counts every comment in the tree, including nested replies.
+func countComments(comments []interface{}) int {
+	count := 0
+	for _, c := range comments {
+		commentMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		count++
+		if replies, ok := commentMap["replies"].([]interface{}); ok {
+			count += countComments(replies)
+		}
+	}
+	return count
+}
+
+func writeJSONPost(fs storage.Storage, post map[string]interface{}, outputPath string) (int64, error) {
+	outFile, err := fs.Create(outputPath)
+	if err != nil {
+		return 0, err
+	}
+	defer outFile.Close()
+
+	counting := telemetry.NewCountingWriter(outFile)
+	encoder := json.NewEncoder(counting)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(post); err != nil {
+		return counting.Bytes(), err
+	}
+	return counting.Bytes(), nil
+}
+
+func writeMarkdownThread(ctx context.Context, fs storage.Storage, post map[string]interface{}, outputPath string, tmpl mdtemplate.Set) (int64, error) {
+	outFile, err := fs.Create(outputPath)
+	if err != nil {
+		return 0, err
+	}
+	defer outFile.Close()
+
+	counting := telemetry.NewCountingWriter(outFile)
+	if err := renderThreadMarkdown(ctx, counting, post, tmpl); err != nil {
+		return counting.Bytes(), err
+	}
+	return counting.Bytes(), nil
+}
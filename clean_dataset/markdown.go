@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/NasoohOlabi/stego-side-wing/mdtemplate"
+)
+
+// renderThreadMarkdown writes post as a Markdown thread: YAML front-matter,
+// the selftext body, then every comment as a block-quote nested one level
+// per reply depth. It walks the same comment tree as cleanComments via
+// walkComments, so the two never disagree about tree shape. If ctx is
+// cancelled partway through the comment tree, it returns ctx.Err() instead
+// of silently emitting a truncated thread.
+func renderThreadMarkdown(ctx context.Context, w io.Writer, post map[string]interface{}, tmpl mdtemplate.Set) error {
+	frontMatter := mdtemplate.FrontMatter{
+		Author:    stringField(post, "author"),
+		Subreddit: stringField(post, "subreddit"),
+		Score:     numberField(post, "score"),
+		Permalink: stringField(post, "permalink"),
+		Created:   numberField(post, "created"),
+	}
+	if err := tmpl.FrontMatter.Execute(w, frontMatter); err != nil {
+		return err
+	}
+
+	body := mdtemplate.Post{
+		Title:    stringField(post, "title"),
+		Selftext: stringField(post, "selftext"),
+	}
+	if err := tmpl.Body.Execute(w, body); err != nil {
+		return err
+	}
+
+	comments, ok := post["comments"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	_, err := walkComments(ctx, comments, 0, func(c map[string]interface{}, depth int) (map[string]interface{}, error) {
+		comment := mdtemplate.Comment{
+			Quote:  strings.Repeat("> ", depth+1),
+			Author: stringField(c, "author"),
+			Score:  numberField(c, "score"),
+			Body:   stringField(c, "body"),
+		}
+		if err := tmpl.Comment.Execute(w, comment); err != nil {
+			return nil, err
+		}
+		return c, nil
+	})
+	return err
+}
+
+func stringField(m map[string]interface{}, key string) string {
+	if s, ok := m[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+// numberField reads key as a float64, defaulting to 0 when it's absent or
+// not a number; JSON numbers decode to float64 in a map[string]interface{},
+// so this is how every numeric field (score, created, ...) comes through.
+// Handing the raw interface{} to a template instead renders the literal
+// string "<no value>" whenever the field was dropped by the post
+// allowlist/comment blocklist or was simply absent from the source.
+func numberField(m map[string]interface{}, key string) float64 {
+	if n, ok := m[key].(float64); ok {
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/NasoohOlabi/stego-side-wing/mdtemplate"
+)
+
+// TestRenderThreadMarkdownMissingFields reproduces a post/comment pair
+// missing "score" and "created" (common whenever a profile's post_allow or
+// comment_block drops them, or the source never had them): the rendered
+// Markdown must not contain the literal "<no value>" text/template leaves
+// behind when handed a raw interface{} for an absent field.
+func TestRenderThreadMarkdownMissingFields(t *testing.T) {
+	tmpl, err := mdtemplate.Default()
+	if err != nil {
+		t.Fatalf("mdtemplate.Default: %v", err)
+	}
+
+	post := map[string]interface{}{
+		"author":    "alice",
+		"subreddit": "golang",
+		"title":     "no score here",
+		"selftext":  "body text",
+		"comments": []interface{}{
+			map[string]interface{}{"author": "bob", "body": "nice post"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderThreadMarkdown(context.Background(), &buf, post, tmpl); err != nil {
+		t.Fatalf("renderThreadMarkdown: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "<no value>") {
+		t.Errorf("rendered markdown leaked a missing-field placeholder:\n%s", out)
+	}
+	if !strings.Contains(out, "score: 0") {
+		t.Errorf("front matter should default a missing score to 0, got:\n%s", out)
+	}
+	if !strings.Contains(out, "**bob** (0): nice post") {
+		t.Errorf("comment should default a missing score to 0, got:\n%s", out)
+	}
+}
+
+// TestRenderThreadMarkdownWithScores checks that present numeric fields
+// still render their actual values, not just the zero-value fallback.
+func TestRenderThreadMarkdownWithScores(t *testing.T) {
+	tmpl, err := mdtemplate.Default()
+	if err != nil {
+		t.Fatalf("mdtemplate.Default: %v", err)
+	}
+
+	post := map[string]interface{}{
+		"author":   "alice",
+		"title":    "has a score",
+		"selftext": "body",
+		"score":    float64(42),
+		"comments": []interface{}{
+			map[string]interface{}{"author": "bob", "body": "hi", "score": float64(7)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderThreadMarkdown(context.Background(), &buf, post, tmpl); err != nil {
+		t.Fatalf("renderThreadMarkdown: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "score: 42") {
+		t.Errorf("front matter should render the real score, got:\n%s", out)
+	}
+	if !strings.Contains(out, "**bob** (7): hi") {
+		t.Errorf("comment should render the real score, got:\n%s", out)
+	}
+}
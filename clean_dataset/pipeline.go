@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+
+	"github.com/NasoohOlabi/stego-side-wing/config"
+)
+
+// sanitizeValue checks if the interface is a string and if it is a bad string
+// (per policy.SanitizeMarkers) returns nil, otherwise returns the original value.
+func sanitizeValue(val interface{}, policy config.Policy) interface{} {
+	if s, ok := val.(string); ok {
+		if _, bad := policy.SanitizeMarkers[s]; bad {
+			return nil
+		}
+	}
+	return val
+}
+
+// cleanPost applies the allowlist/blocklist/sanitize pipeline to a single
+// decoded post, shared by both the one-file-per-post mode and the ndjson
+// dump ingestion mode. It reports whether the post should be skipped
+// (comments missing or empty after cleaning). ctx is checked before and
+// during the comment tree walk; if it is cancelled partway through, cleanPost
+// returns ctx.Err() instead of a half-cleaned post, so a caller never writes
+// or records output that still contains blocklisted fields or un-sanitized
+// markers.
+func cleanPost(ctx context.Context, post map[string]interface{}, policy config.Policy) (cleaned map[string]interface{}, skip bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	// 1. Filter Post Fields AND Sanitize Values
+	for k, v := range post {
+		// Check Allowlist
+		if _, keep := policy.PostAllow[k]; !keep {
+			delete(post, k)
+			continue
+		}
+		// Check Value Content (Sanitization)
+		post[k] = sanitizeValue(v, policy)
+	}
+
+	// 2. Recursive Comment Cleaning
+	if comments, ok := post["comments"]; ok {
+		// Ensure comments is actually a list before processing
+		if commentsSlice, ok := comments.([]interface{}); ok {
+			cleanedComments, err := cleanComments(ctx, commentsSlice, policy)
+			if err != nil {
+				return nil, false, err
+			}
+			post["comments"] = cleanedComments
+		}
+	}
+
+	// 3. Skip if comments is null or empty
+	if comments, exists := post["comments"]; !exists || comments == nil {
+		return post, true, nil
+	} else if commentsSlice, ok := comments.([]interface{}); ok && len(commentsSlice) == 0 {
+		return post, true, nil
+	}
+
+	return post, false, nil
+}
+
+func cleanComments(ctx context.Context, comments []interface{}, policy config.Policy) ([]interface{}, error) {
+	return walkComments(ctx, comments, 0, func(commentMap map[string]interface{}, depth int) (map[string]interface{}, error) {
+		// Iterate over all keys in the comment to Blocklist fields AND Sanitize values
+		for k, v := range commentMap {
+			// Check Blocklist
+			if _, isUseless := policy.CommentBlock[k]; isUseless {
+				delete(commentMap, k)
+				continue
+			}
+			// Check Value Content (Sanitization)
+			commentMap[k] = sanitizeValue(v, policy)
+		}
+		return commentMap, nil
+	})
+}
+
+// walkComments recurses through a comment tree depth-first, applying visit
+// to every node before descending into its "replies". It is the single
+// traversal shared by cleanComments (which mutates/filters fields) and the
+// Markdown renderer (which reads fields at each depth) so the two never
+// drift apart on how the reply tree is walked. It stops descending and
+// returns ctx.Err() as soon as ctx is cancelled, or as soon as visit itself
+// returns an error, rather than returning a partially-walked tree as if it
+// were complete.
+func walkComments(ctx context.Context, comments []interface{}, depth int, visit func(comment map[string]interface{}, depth int) (map[string]interface{}, error)) ([]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	walked := make([]interface{}, 0, len(comments))
+
+	for _, c := range comments {
+		commentMap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		commentMap, err := visit(commentMap, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		// Handle recursion on "replies"
+		if replies, exists := commentMap["replies"]; exists {
+			// Note: visit might have turned "replies" into nil (e.g. sanitized
+			// away), so we check it's still a valid list before recursing.
+			if repliesList, isList := replies.([]interface{}); isList {
+				walkedReplies, err := walkComments(ctx, repliesList, depth+1, visit)
+				if err != nil {
+					return nil, err
+				}
+				commentMap["replies"] = walkedReplies
+			}
+		}
+
+		walked = append(walked, commentMap)
+	}
+	return walked, nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/NasoohOlabi/stego-side-wing/config"
+	"github.com/NasoohOlabi/stego-side-wing/manifest"
+	"github.com/NasoohOlabi/stego-side-wing/mdtemplate"
+	"github.com/NasoohOlabi/stego-side-wing/storage"
+	"github.com/NasoohOlabi/stego-side-wing/telemetry"
+)
+
+func testPolicy() config.Policy {
+	return config.Policy{
+		PostAllow:       map[string]struct{}{"title": {}, "comments": {}, "selftext": {}},
+		CommentBlock:    map[string]struct{}{"gilded": {}},
+		SanitizeMarkers: map[string]struct{}{"[removed]": {}},
+	}
+}
+
+// TestRunFileModeEndToEnd exercises the whole files-mode pipeline against
+// two in-memory filesystems, verifying allowlist filtering, blocklist
+// filtering, sanitization, and the empty-comments skip rule without
+// touching disk.
+func TestRunFileModeEndToEnd(t *testing.T) {
+	input := storage.NewMemFS()
+	output := storage.NewMemFS()
+	ds := dataset{Input: input, InputDir: "in", Output: output, OutputDir: "out"}
+	policy := testPolicy()
+	policy.InputDir = "in"
+	policy.OutputDir = "out"
+
+	kept, err := json.Marshal(map[string]interface{}{
+		"title":     "hello",
+		"selftext":  "[removed]",
+		"subreddit": "should be dropped by the allowlist",
+		"comments": []interface{}{
+			map[string]interface{}{"body": "nice post", "gilded": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal kept fixture: %v", err)
+	}
+	input.Put("in/kept.json", kept)
+
+	empty, err := json.Marshal(map[string]interface{}{
+		"title":    "no replies",
+		"comments": []interface{}{},
+	})
+	if err != nil {
+		t.Fatalf("marshal empty fixture: %v", err)
+	}
+	input.Put("in/empty.json", empty)
+
+	templates, err := mdtemplate.Default()
+	if err != nil {
+		t.Fatalf("mdtemplate.Default: %v", err)
+	}
+	mf := mustEmptyManifest(t)
+	logger, err := telemetry.NewLogger("error", "text")
+	if err != nil {
+		t.Fatalf("telemetry.NewLogger: %v", err)
+	}
+
+	if err := runFileMode(context.Background(), ds, policy, outputOptions{Mode: "json", Templates: templates}, mf, false, logger, ""); err != nil {
+		t.Fatalf("runFileMode: %v", err)
+	}
+
+	if _, ok := output.Get("out/empty.json"); ok {
+		t.Error("empty.json should have been skipped (no comments), but was written")
+	}
+
+	data, ok := output.Get("out/kept.json")
+	if !ok {
+		t.Fatal("kept.json was not written")
+	}
+
+	var cleaned map[string]interface{}
+	if err := json.Unmarshal(data, &cleaned); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+
+	if _, present := cleaned["subreddit"]; present {
+		t.Error("subreddit should have been dropped by the post allowlist")
+	}
+	if cleaned["selftext"] != nil {
+		t.Errorf("selftext = %v, want nil (sanitized [removed])", cleaned["selftext"])
+	}
+
+	comments, ok := cleaned["comments"].([]interface{})
+	if !ok || len(comments) != 1 {
+		t.Fatalf("comments = %v, want one comment", cleaned["comments"])
+	}
+	comment := comments[0].(map[string]interface{})
+	if _, present := comment["gilded"]; present {
+		t.Error("gilded should have been dropped by the comment blocklist")
+	}
+	if comment["body"] != "nice post" {
+		t.Errorf("body = %v, want %q", comment["body"], "nice post")
+	}
+}
+
+// TestCleanPostPropagatesCancellation reproduces a SIGINT/SIGTERM landing
+// mid-clean: cleanPost must return ctx.Err() rather than a post whose
+// comments were only partially blocklist-filtered/sanitized, so a caller
+// never mistakes a half-cleaned result for a completed one.
+func TestCleanPostPropagatesCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	post := map[string]interface{}{
+		"title": "hello",
+		"comments": []interface{}{
+			map[string]interface{}{"body": "hi", "gilded": true},
+		},
+	}
+
+	_, _, err := cleanPost(ctx, post, testPolicy())
+	if err == nil {
+		t.Fatal("cleanPost should return an error when ctx is already cancelled, got nil")
+	}
+}
+
+// TestRunFileModeSkipsFileOnCancellation reproduces the end-to-end leak
+// scenario: when ctx is cancelled before an in-flight file is processed, it
+// must not be written to the output or recorded in the manifest, so a
+// re-run will still pick it up instead of treating it as already cleaned.
+func TestRunFileModeSkipsFileOnCancellation(t *testing.T) {
+	input := storage.NewMemFS()
+	output := storage.NewMemFS()
+	ds := dataset{Input: input, InputDir: "in", Output: output, OutputDir: "out"}
+	policy := testPolicy()
+	policy.InputDir = "in"
+	policy.OutputDir = "out"
+
+	post, err := json.Marshal(map[string]interface{}{
+		"title": "hello",
+		"comments": []interface{}{
+			map[string]interface{}{"body": "hi", "gilded": true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("marshal fixture: %v", err)
+	}
+	input.Put("in/post.json", post)
+
+	templates, err := mdtemplate.Default()
+	if err != nil {
+		t.Fatalf("mdtemplate.Default: %v", err)
+	}
+	mf := mustEmptyManifest(t)
+	logger, err := telemetry.NewLogger("error", "text")
+	if err != nil {
+		t.Fatalf("telemetry.NewLogger: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := runFileMode(ctx, ds, policy, outputOptions{Mode: "json", Templates: templates}, mf, false, logger, ""); err != nil {
+		t.Fatalf("runFileMode: %v", err)
+	}
+
+	if _, ok := output.Get("out/post.json"); ok {
+		t.Error("post.json should not have been written once ctx was cancelled")
+	}
+	if _, ok := mf.Get("post.json"); ok {
+		t.Error("post.json should not have been recorded in the manifest once ctx was cancelled")
+	}
+}
+
+func mustEmptyManifest(t *testing.T) *manifest.Manifest {
+	t.Helper()
+	mf, err := manifest.Load(t.TempDir() + "/.manifest.json")
+	if err != nil {
+		t.Fatalf("manifest.Load: %v", err)
+	}
+	return mf
+}
@@ -0,0 +1,78 @@
+// Package config loads TOML-defined cleaning profiles for the dataset
+// cleaner: per-dataset directories, field policies and sanitize markers.
+package config
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Profile is a single named cleaning configuration, e.g. [profiles.news].
+type Profile struct {
+	InputDir        string   `toml:"input_dir"`
+	OutputDir       string   `toml:"output_dir"`
+	PostAllow       []string `toml:"post_allow"`
+	CommentBlock    []string `toml:"comment_block"`
+	SanitizeMarkers []string `toml:"sanitize_markers"`
+	Concurrency     int      `toml:"concurrency"`
+}
+
+// File is the top-level shape of a cleaner.toml config file.
+type File struct {
+	Profiles map[string]Profile `toml:"profiles"`
+}
+
+// Policy is the resolved, ready-to-use form of a Profile: string slices are
+// turned into lookup sets so processFile/cleanComments can test membership
+// without rebuilding maps per call.
+type Policy struct {
+	InputDir        string
+	OutputDir       string
+	Concurrency     int
+	PostAllow       map[string]struct{}
+	CommentBlock    map[string]struct{}
+	SanitizeMarkers map[string]struct{}
+}
+
+// Load parses a cleaner.toml file at path.
+func Load(path string) (*File, error) {
+	var f File
+	if _, err := toml.DecodeFile(path, &f); err != nil {
+		return nil, fmt.Errorf("config: decode %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Resolve looks up profile by name and returns it as a ready-to-use Policy.
+func (f *File) Resolve(name string) (Policy, error) {
+	profile, ok := f.Profiles[name]
+	if !ok {
+		return Policy{}, fmt.Errorf("config: no such profile %q", name)
+	}
+	return Policy{
+		InputDir:        profile.InputDir,
+		OutputDir:       profile.OutputDir,
+		Concurrency:     profile.Concurrency,
+		PostAllow:       toSet(profile.PostAllow),
+		CommentBlock:    toSet(profile.CommentBlock),
+		SanitizeMarkers: toSet(profile.SanitizeMarkers),
+	}, nil
+}
+
+// LoadProfile is a convenience wrapper combining Load and Resolve.
+func LoadProfile(path, name string) (Policy, error) {
+	f, err := Load(path)
+	if err != nil {
+		return Policy{}, err
+	}
+	return f.Resolve(name)
+}
+
+func toSet(values []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
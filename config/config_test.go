@@ -0,0 +1,91 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const sampleTOML = `
+[profiles.news]
+input_dir = "../datasets/news"
+output_dir = "../datasets/news_cleaned"
+post_allow = ["author", "title", "comments"]
+comment_block = ["gilded", "saved"]
+sanitize_markers = ["[removed]", "[deleted]"]
+concurrency = 8
+
+[profiles.javahelp]
+input_dir = "../datasets/javahelp"
+output_dir = "../datasets/javahelp_cleaned"
+post_allow = ["author", "selftext"]
+comment_block = ["saved"]
+sanitize_markers = ["[removed]"]
+concurrency = 4
+`
+
+func writeSample(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cleaner.toml")
+	if err := os.WriteFile(path, []byte(sampleTOML), 0644); err != nil {
+		t.Fatalf("write sample config: %v", err)
+	}
+	return path
+}
+
+func TestLoadAndResolve(t *testing.T) {
+	path := writeSample(t)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(f.Profiles) != 2 {
+		t.Fatalf("expected 2 profiles, got %d", len(f.Profiles))
+	}
+
+	policy, err := f.Resolve("news")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if policy.InputDir != "../datasets/news" {
+		t.Errorf("InputDir = %q, want %q", policy.InputDir, "../datasets/news")
+	}
+	if policy.Concurrency != 8 {
+		t.Errorf("Concurrency = %d, want 8", policy.Concurrency)
+	}
+	if _, ok := policy.PostAllow["title"]; !ok {
+		t.Errorf("PostAllow missing %q", "title")
+	}
+	if _, ok := policy.CommentBlock["gilded"]; !ok {
+		t.Errorf("CommentBlock missing %q", "gilded")
+	}
+	if _, ok := policy.SanitizeMarkers["[removed]"]; !ok {
+		t.Errorf("SanitizeMarkers missing %q", "[removed]")
+	}
+}
+
+func TestResolveUnknownProfile(t *testing.T) {
+	path := writeSample(t)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := f.Resolve("does-not-exist"); err == nil {
+		t.Fatal("expected an error for an unknown profile, got nil")
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := writeSample(t)
+
+	policy, err := LoadProfile(path, "javahelp")
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if policy.Concurrency != 4 {
+		t.Errorf("Concurrency = %d, want 4", policy.Concurrency)
+	}
+}
@@ -0,0 +1,127 @@
+// Package manifest tracks which input files have already been cleaned, by
+// content hash, so a re-run of the pipeline can skip unchanged files and
+// resume exactly where a previous run left off.
+package manifest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry records everything needed to decide whether an input file needs
+// reprocessing, and where its output landed.
+type Entry struct {
+	InputSHA256  string    `json:"input_sha256"`
+	ModTime      time.Time `json:"mod_time"`
+	OutputPath   string    `json:"output_path"`
+	OutputSHA256 string    `json:"output_sha256,omitempty"`
+	LastSuccess  time.Time `json:"last_success"`
+}
+
+// Manifest is a concurrency-safe, file-backed index keyed by input filename.
+type Manifest struct {
+	path string
+
+	mu      sync.Mutex
+	Entries map[string]Entry `json:"entries"`
+}
+
+// FileName is the conventional manifest filename inside an output directory.
+const FileName = ".manifest.json"
+
+// Path returns the conventional manifest path for an output directory.
+func Path(outputDir string) string {
+	return filepath.Join(outputDir, FileName)
+}
+
+// Load reads the manifest at path, returning an empty Manifest if it does
+// not exist yet.
+func Load(path string) (*Manifest, error) {
+	m := &Manifest{path: path, Entries: make(map[string]Entry)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &m.Entries); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Unchanged reports whether name is already recorded with the given input
+// hash, meaning it can be skipped.
+func (m *Manifest) Unchanged(name, inputSHA256 string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.Entries[name]
+	return ok && entry.InputSHA256 == inputSHA256
+}
+
+// Get returns the recorded entry for name, if any.
+func (m *Manifest) Get(name string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.Entries[name]
+	return entry, ok
+}
+
+// Record stores (or replaces) the entry for name.
+func (m *Manifest) Record(name string, entry Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Entries[name] = entry
+}
+
+// Save atomically persists the manifest to its path: it writes to a
+// temporary file and renames it into place, so a crash mid-write never
+// leaves a corrupt manifest behind.
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, err := json.MarshalIndent(m.Entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := m.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, m.path)
+}
+
+// HashFile returns the hex-encoded SHA-256 of the local file at path.
+func HashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return Hash(f)
+}
+
+// Hash returns the hex-encoded SHA-256 of everything read from r, for
+// callers reading through a storage.Storage backend rather than a local
+// *os.File.
+func Hash(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,76 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingIsEmpty(t *testing.T) {
+	m, err := Load(filepath.Join(t.TempDir(), ".manifest.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(m.Entries) != 0 {
+		t.Fatalf("expected an empty manifest, got %d entries", len(m.Entries))
+	}
+	if m.Unchanged("post1.json", "deadbeef") {
+		t.Error("Unchanged should be false for an unknown entry")
+	}
+}
+
+func TestRecordSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".manifest.json")
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	entry := Entry{
+		InputSHA256: "abc123",
+		ModTime:     time.Unix(1000, 0).UTC(),
+		OutputPath:  "out/post1",
+		LastSuccess: time.Unix(2000, 0).UTC(),
+	}
+	m.Record("post1.json", entry)
+
+	if err := m.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	got, ok := reloaded.Get("post1.json")
+	if !ok {
+		t.Fatal("expected post1.json entry after reload")
+	}
+	if got.InputSHA256 != entry.InputSHA256 {
+		t.Errorf("InputSHA256 = %q, want %q", got.InputSHA256, entry.InputSHA256)
+	}
+	if !reloaded.Unchanged("post1.json", "abc123") {
+		t.Error("expected Unchanged to be true for a matching hash")
+	}
+	if reloaded.Unchanged("post1.json", "different") {
+		t.Error("expected Unchanged to be false for a different hash")
+	}
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sample.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+		t.Fatalf("write sample: %v", err)
+	}
+
+	hash, err := HashFile(path)
+	if err != nil {
+		t.Fatalf("HashFile: %v", err)
+	}
+	const want = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	if hash != want {
+		t.Errorf("HashFile = %q, want %q", hash, want)
+	}
+}
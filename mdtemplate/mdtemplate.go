@@ -0,0 +1,111 @@
+// Package mdtemplate holds the text/template layouts used to render a
+// cleaned post as a Markdown thread: YAML front-matter, the post body, and
+// one line per comment. Callers can swap in their own templates without
+// touching the renderer's Go code.
+package mdtemplate
+
+import (
+	"fmt"
+	"os"
+	"text/template"
+)
+
+// FrontMatter is the data passed to the FrontMatter template.
+type FrontMatter struct {
+	Author    string
+	Subreddit string
+	Score     float64
+	Permalink string
+	Created   float64
+}
+
+// Post is the data passed to the Body template.
+type Post struct {
+	Title    string
+	Selftext string
+}
+
+// Comment is the data passed to the Comment template, rendered once per
+// node while walking the reply tree.
+type Comment struct {
+	Quote  string // blockquote prefix, repeated per reply depth
+	Author string
+	Score  float64
+	Body   string
+}
+
+const defaultFrontMatter = `---
+author: {{.Author}}
+subreddit: {{.Subreddit}}
+score: {{.Score}}
+permalink: {{.Permalink}}
+created: {{.Created}}
+---
+
+`
+
+const defaultBody = `# {{.Title}}
+
+{{.Selftext}}
+
+`
+
+const defaultComment = `{{.Quote}}**{{.Author}}** ({{.Score}}): {{.Body}}
+`
+
+// Set is the parsed templates needed to render one thread.
+type Set struct {
+	FrontMatter *template.Template
+	Body        *template.Template
+	Comment     *template.Template
+}
+
+// Default returns the built-in templates.
+func Default() (Set, error) {
+	return parse(defaultFrontMatter, defaultBody, defaultComment)
+}
+
+// Load parses templates from disk, falling back to the built-in default for
+// any path left empty.
+func Load(frontMatterPath, bodyPath, commentPath string) (Set, error) {
+	frontMatter, err := readOrDefault(frontMatterPath, defaultFrontMatter)
+	if err != nil {
+		return Set{}, err
+	}
+	body, err := readOrDefault(bodyPath, defaultBody)
+	if err != nil {
+		return Set{}, err
+	}
+	comment, err := readOrDefault(commentPath, defaultComment)
+	if err != nil {
+		return Set{}, err
+	}
+	return parse(frontMatter, body, comment)
+}
+
+func readOrDefault(path, fallback string) (string, error) {
+	if path == "" {
+		return fallback, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("mdtemplate: read %s: %w", path, err)
+	}
+	return string(content), nil
+}
+
+func parse(frontMatter, body, comment string) (Set, error) {
+	fm, err := template.New("front-matter").Parse(frontMatter)
+	if err != nil {
+		return Set{}, fmt.Errorf("mdtemplate: parse front-matter: %w", err)
+	}
+	b, err := template.New("body").Parse(body)
+	if err != nil {
+		return Set{}, fmt.Errorf("mdtemplate: parse body: %w", err)
+	}
+	c, err := template.New("comment").Parse(comment)
+	if err != nil {
+		return Set{}, fmt.Errorf("mdtemplate: parse comment: %w", err)
+	}
+	return Set{FrontMatter: fm, Body: b, Comment: c}, nil
+}
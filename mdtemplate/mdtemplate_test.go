@@ -0,0 +1,50 @@
+package mdtemplate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultRenders(t *testing.T) {
+	set, err := Default()
+	if err != nil {
+		t.Fatalf("Default: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := set.FrontMatter.Execute(&buf, FrontMatter{Author: "alice", Subreddit: "golang"}); err != nil {
+		t.Fatalf("FrontMatter.Execute: %v", err)
+	}
+	if got := buf.String(); !bytes.Contains([]byte(got), []byte("author: alice")) {
+		t.Errorf("front-matter missing author, got:\n%s", got)
+	}
+}
+
+func TestLoadCustomTemplate(t *testing.T) {
+	dir := t.TempDir()
+	commentPath := filepath.Join(dir, "comment.tmpl")
+	if err := os.WriteFile(commentPath, []byte("{{.Quote}}{{.Author}} says: {{.Body}}\n"), 0644); err != nil {
+		t.Fatalf("write custom template: %v", err)
+	}
+
+	set, err := Load("", "", commentPath)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := set.Comment.Execute(&buf, Comment{Quote: "> ", Author: "bob", Body: "hi"}); err != nil {
+		t.Fatalf("Comment.Execute: %v", err)
+	}
+	if got, want := buf.String(), "> bob says: hi\n"; got != want {
+		t.Errorf("rendered comment = %q, want %q", got, want)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load("/no/such/file.tmpl", "", ""); err == nil {
+		t.Fatal("expected an error for a missing template file, got nil")
+	}
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalFS is the default Storage backend: it reads and writes the real
+// filesystem via os and path/filepath, exactly as the pipeline did before
+// the Storage abstraction existed.
+type LocalFS struct{}
+
+func (LocalFS) Open(name string) (io.ReadCloser, error) {
+	return os.Open(name)
+}
+
+func (LocalFS) Create(name string) (io.WriteCloser, error) {
+	return os.Create(name)
+}
+
+func (LocalFS) ReadDir(dirname string) ([]string, error) {
+	entries, err := os.ReadDir(dirname)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+func (LocalFS) MkdirAll(dirname string) error {
+	return os.MkdirAll(dirname, 0755)
+}
+
+// Join mirrors filepath.Join for callers building paths to pass to a
+// Storage; LocalFS and MemFS both treat "/" as the separator, so this is
+// safe to use regardless of which backend is in play.
+func Join(elem ...string) string {
+	return filepath.Join(elem...)
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var errNotExist = os.ErrNotExist
+
+// MemFS is an in-memory Storage backend, used to exercise the whole
+// cleaning pipeline in unit tests without touching disk.
+type MemFS struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemFS returns an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string][]byte)}
+}
+
+// Put seeds a file directly, for setting up test fixtures.
+func (m *MemFS) Put(name string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = data
+}
+
+// Get returns the bytes written to name, for asserting on test output.
+func (m *MemFS) Get(name string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[name]
+	return data, ok
+}
+
+func (m *MemFS) Open(name string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	data, ok := m.files[name]
+	if !ok {
+		return nil, fmt.Errorf("memfs: open %s: %w", name, errNotExist)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (m *MemFS) Create(name string) (io.WriteCloser, error) {
+	return &memFile{fs: m, name: name}, nil
+}
+
+func (m *MemFS) ReadDir(dirname string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := strings.TrimSuffix(dirname, "/") + "/"
+	var names []string
+	for name := range m.files {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || strings.Contains(rest, "/") {
+			continue
+		}
+		names = append(names, rest)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *MemFS) MkdirAll(dirname string) error {
+	return nil
+}
+
+type memFile struct {
+	fs   *MemFS
+	name string
+	buf  bytes.Buffer
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	return f.buf.Write(p)
+}
+
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	f.fs.files[f.name] = append([]byte(nil), f.buf.Bytes()...)
+	return nil
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3FS is a Storage backend over a single S3 (or S3-compatible, e.g. MinIO)
+// bucket. Credentials, region and a custom endpoint are read from the
+// standard AWS_* environment variables plus STEGO_S3_ENDPOINT, so no
+// dataset-specific config is needed to point at a non-AWS endpoint.
+type S3FS struct {
+	client *s3.Client
+	bucket string
+}
+
+// NewS3FS builds an S3FS for bucket from environment configuration:
+//
+//	AWS_ACCESS_KEY_ID / AWS_SECRET_ACCESS_KEY / AWS_SESSION_TOKEN - credentials
+//	AWS_REGION (falls back to AWS_DEFAULT_REGION, then "us-east-1")
+//	STEGO_S3_ENDPOINT - optional custom endpoint, e.g. for MinIO
+func NewS3FS(bucket string) (*S3FS, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	cfg := aws.Config{
+		Region: region,
+		Credentials: credentials.NewStaticCredentialsProvider(
+			os.Getenv("AWS_ACCESS_KEY_ID"),
+			os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			os.Getenv("AWS_SESSION_TOKEN"),
+		),
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if endpoint := os.Getenv("STEGO_S3_ENDPOINT"); endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+
+	return &S3FS{client: client, bucket: bucket}, nil
+}
+
+func (s *S3FS) Open(name string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(context.Background(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: s3 get s3://%s/%s: %w", s.bucket, name, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3FS) Create(name string) (io.WriteCloser, error) {
+	return &s3Writer{fs: s, key: name}, nil
+}
+
+func (s *S3FS) ReadDir(dirname string) ([]string, error) {
+	prefix := strings.TrimSuffix(dirname, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	var names []string
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("storage: s3 list s3://%s/%s: %w", s.bucket, prefix, err)
+		}
+		for _, obj := range page.Contents {
+			names = append(names, strings.TrimPrefix(aws.ToString(obj.Key), prefix))
+		}
+	}
+	return names, nil
+}
+
+// MkdirAll is a no-op: S3 has no real directories, only key prefixes.
+func (s *S3FS) MkdirAll(dirname string) error {
+	return nil
+}
+
+type s3Writer struct {
+	fs  *S3FS
+	key string
+	buf bytes.Buffer
+}
+
+func (w *s3Writer) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+func (w *s3Writer) Close() error {
+	_, err := w.fs.client.PutObject(context.Background(), &s3.PutObjectInput{
+		Bucket: aws.String(w.fs.bucket),
+		Key:    aws.String(w.key),
+		Body:   bytes.NewReader(w.buf.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("storage: s3 put s3://%s/%s: %w", w.fs.bucket, w.key, err)
+	}
+	return nil
+}
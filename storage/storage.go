@@ -0,0 +1,43 @@
+// Package storage abstracts the filesystem operations the cleaning
+// pipeline needs (Open, Create, ReadDir, MkdirAll) behind a small Storage
+// interface, so the same pipeline code can run against local disk, an
+// in-memory filesystem (for tests), or an S3/MinIO bucket.
+package storage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Storage is the minimal filesystem surface the cleaning pipeline needs.
+// Paths are always "/"-separated, relative to whatever root the
+// implementation was constructed with.
+type Storage interface {
+	Open(name string) (io.ReadCloser, error)
+	Create(name string) (io.WriteCloser, error)
+	ReadDir(dirname string) ([]string, error)
+	MkdirAll(dirname string) error
+}
+
+// New resolves rawPath into a Storage backend and the path that backend
+// should use for subsequent calls: an "s3://bucket/prefix" URL yields an
+// S3FS rooted at bucket with prefix as the resolved path, while anything
+// else is treated as a local disk path served by LocalFS unchanged.
+func New(rawPath string) (fs Storage, resolvedPath string, err error) {
+	if !strings.HasPrefix(rawPath, "s3://") {
+		return LocalFS{}, rawPath, nil
+	}
+
+	trimmed := strings.TrimPrefix(rawPath, "s3://")
+	bucket, prefix, _ := strings.Cut(trimmed, "/")
+	if bucket == "" {
+		return nil, "", fmt.Errorf("storage: %q is missing a bucket name", rawPath)
+	}
+
+	s3fs, err := NewS3FS(bucket)
+	if err != nil {
+		return nil, "", err
+	}
+	return s3fs, prefix, nil
+}
@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testStorage(t *testing.T, fs Storage) {
+	t.Helper()
+
+	if err := fs.MkdirAll("out"); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+
+	w, err := fs.Create("out/a.txt")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	r, err := fs.Open("out/a.txt")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("read %q, want %q", data, "hello")
+	}
+
+	names, err := fs.ReadDir("out")
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(names) != 1 || names[0] != "a.txt" {
+		t.Errorf("ReadDir = %v, want [a.txt]", names)
+	}
+
+	if _, err := fs.Open("out/missing.txt"); err == nil {
+		t.Error("expected an error opening a missing file")
+	}
+}
+
+func TestMemFS(t *testing.T) {
+	testStorage(t, NewMemFS())
+}
+
+func TestLocalFS(t *testing.T) {
+	dir := t.TempDir()
+	fs := LocalFS{}
+
+	// LocalFS paths are real filesystem paths, so root the test under dir.
+	mk := func(name string) string { return filepath.Join(dir, name) }
+
+	if err := fs.MkdirAll(mk("out")); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	w, err := fs.Create(mk("out/a.txt"))
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := w.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(mk("out/a.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Errorf("read %q, want %q", data, "hello")
+	}
+
+	names, err := fs.ReadDir(mk("out"))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(names) != 1 || names[0] != "a.txt" {
+		t.Errorf("ReadDir = %v, want [a.txt]", names)
+	}
+}
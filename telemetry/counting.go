@@ -0,0 +1,45 @@
+package telemetry
+
+import "io"
+
+// CountingReader wraps r, tracking how many bytes have been read through it.
+type CountingReader struct {
+	r     io.Reader
+	bytes int64
+}
+
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r}
+}
+
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.bytes += int64(n)
+	return n, err
+}
+
+// Bytes returns the number of bytes read so far.
+func (c *CountingReader) Bytes() int64 {
+	return c.bytes
+}
+
+// CountingWriter wraps w, tracking how many bytes have been written through it.
+type CountingWriter struct {
+	w     io.Writer
+	bytes int64
+}
+
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	return &CountingWriter{w: w}
+}
+
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.bytes += int64(n)
+	return n, err
+}
+
+// Bytes returns the number of bytes written so far.
+func (c *CountingWriter) Bytes() int64 {
+	return c.bytes
+}
@@ -0,0 +1,80 @@
+// Package telemetry provides structured, leveled logging and a live
+// progress reporter (with an optional Prometheus /metrics endpoint) for the
+// dataset cleaning pipeline, so long-running jobs over large dumps stay
+// observable instead of fire-and-forget.
+package telemetry
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewLogger builds a slog.Logger writing to stdout at the given level
+// ("debug", "info", "warn", "error") in the given format ("text" or
+// "json"). Empty values default to "info" and "text".
+func NewLogger(level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "", "info":
+		lvl = slog.LevelInfo
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		return nil, fmt.Errorf("telemetry: unknown -log-level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	default:
+		return nil, fmt.Errorf("telemetry: unknown -log-format %q", format)
+	}
+	return slog.New(handler), nil
+}
+
+// Event describes one file's pass through the pipeline: the fields a
+// progress reporter needs, plus everything worth logging about it.
+type Event struct {
+	File          string
+	Stage         string
+	Duration      time.Duration
+	BytesIn       int64
+	BytesOut      int64
+	PostsKept     int
+	CommentsKept  int
+	SkippedReason string
+	Err           error
+}
+
+// Log emits one structured event: Info for a clean success, Error when Err
+// is set.
+func (e Event) Log(logger *slog.Logger) {
+	attrs := []any{
+		"file", e.File,
+		"stage", e.Stage,
+		"duration_ms", e.Duration.Milliseconds(),
+		"bytes_in", e.BytesIn,
+		"bytes_out", e.BytesOut,
+		"posts_kept", e.PostsKept,
+		"comments_kept", e.CommentsKept,
+	}
+	if e.SkippedReason != "" {
+		attrs = append(attrs, "skipped_reason", e.SkippedReason)
+	}
+	if e.Err != nil {
+		logger.Error("file processed", append(attrs, "error", e.Err.Error())...)
+		return
+	}
+	logger.Info("file processed", attrs...)
+}
@@ -0,0 +1,140 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// Reporter consumes Events over a channel, logs each one, and folds it into
+// live counters that can be printed periodically or served as Prometheus
+// metrics via its MetricsHandler.
+type Reporter struct {
+	logger *slog.Logger
+	total  int64
+	start  time.Time
+
+	events  chan Event
+	stopped chan struct{}
+
+	done         int64
+	errors       int64
+	bytesIn      int64
+	bytesOut     int64
+	postsKept    int64
+	commentsKept int64
+}
+
+// NewReporter returns a Reporter for a run of `total` files. Call Run in its
+// own goroutine to start consuming, and Submit for every completed file.
+func NewReporter(logger *slog.Logger, total int) *Reporter {
+	return &Reporter{
+		logger:  logger,
+		total:   int64(total),
+		start:   time.Now(),
+		events:  make(chan Event, 64),
+		stopped: make(chan struct{}),
+	}
+}
+
+// Submit logs e and queues it for the live counters. Safe to call from
+// multiple goroutines.
+func (r *Reporter) Submit(e Event) {
+	e.Log(r.logger)
+	r.events <- e
+}
+
+// Close stops accepting Events and waits for Run to drain and return.
+func (r *Reporter) Close() {
+	close(r.events)
+	<-r.stopped
+}
+
+// Run drains events, folding each into the live counters, and prints a
+// progress line every interval until Close is called or ctx is done.
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	defer close(r.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e, ok := <-r.events:
+			if !ok {
+				return
+			}
+			r.apply(e)
+		case <-ticker.C:
+			r.printProgress()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Reporter) apply(e Event) {
+	atomic.AddInt64(&r.done, 1)
+	if e.Err != nil {
+		atomic.AddInt64(&r.errors, 1)
+	}
+	atomic.AddInt64(&r.bytesIn, e.BytesIn)
+	atomic.AddInt64(&r.bytesOut, e.BytesOut)
+	atomic.AddInt64(&r.postsKept, int64(e.PostsKept))
+	atomic.AddInt64(&r.commentsKept, int64(e.CommentsKept))
+}
+
+func (r *Reporter) printProgress() {
+	done := atomic.LoadInt64(&r.done)
+	if done == 0 {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	rate := float64(done) / elapsed
+	errs := atomic.LoadInt64(&r.errors)
+
+	var eta time.Duration
+	if remaining := r.total - done; rate > 0 && remaining > 0 {
+		eta = time.Duration(float64(remaining)/rate) * time.Second
+	}
+
+	fmt.Printf(
+		"progress: %d/%d files (%.1f/s) eta=%s errors=%.1f%% bytes_in=%d bytes_out=%d\n",
+		done, r.total, rate, eta, 100*float64(errs)/float64(done),
+		atomic.LoadInt64(&r.bytesIn), atomic.LoadInt64(&r.bytesOut),
+	)
+}
+
+// MetricsHandler serves the live counters as Prometheus text-format metrics.
+func (r *Reporter) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics := []struct {
+			name, help, kind string
+			value            int64
+		}{
+			{"stego_cleaner_files_total", "Total files queued for this run.", "gauge", r.total},
+			{"stego_cleaner_files_done_total", "Files processed so far.", "counter", atomic.LoadInt64(&r.done)},
+			{"stego_cleaner_errors_total", "Files that failed to process.", "counter", atomic.LoadInt64(&r.errors)},
+			{"stego_cleaner_bytes_in_total", "Bytes read from inputs.", "counter", atomic.LoadInt64(&r.bytesIn)},
+			{"stego_cleaner_bytes_out_total", "Bytes written to outputs.", "counter", atomic.LoadInt64(&r.bytesOut)},
+			{"stego_cleaner_posts_kept_total", "Posts kept after cleaning.", "counter", atomic.LoadInt64(&r.postsKept)},
+			{"stego_cleaner_comments_kept_total", "Comments kept after cleaning.", "counter", atomic.LoadInt64(&r.commentsKept)},
+		}
+		for _, m := range metrics {
+			fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", m.name, m.help, m.name, m.kind, m.name, m.value)
+		}
+	})
+}
+
+// ServeMetrics starts an HTTP server on addr exposing /metrics and blocks
+// until it errors; callers typically run it in its own goroutine.
+func (r *Reporter) ServeMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", r.MetricsHandler())
+	return http.ListenAndServe(addr, mux)
+}
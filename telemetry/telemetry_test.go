@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCountingReaderWriter(t *testing.T) {
+	cr := NewCountingReader(strings.NewReader("hello"))
+	if _, err := io.ReadAll(cr); err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if cr.Bytes() != 5 {
+		t.Errorf("CountingReader.Bytes() = %d, want 5", cr.Bytes())
+	}
+
+	var buf bytes.Buffer
+	cw := NewCountingWriter(&buf)
+	if _, err := cw.Write([]byte("hello!")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if cw.Bytes() != 6 {
+		t.Errorf("CountingWriter.Bytes() = %d, want 6", cw.Bytes())
+	}
+}
+
+func TestNewLoggerRejectsUnknownLevel(t *testing.T) {
+	if _, err := NewLogger("very-loud", "text"); err == nil {
+		t.Fatal("expected an error for an unknown log level")
+	}
+}
+
+func TestNewLoggerRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewLogger("info", "xml"); err == nil {
+		t.Fatal("expected an error for an unknown log format")
+	}
+}
+
+func TestReporterMetricsHandler(t *testing.T) {
+	logger, err := NewLogger("error", "text") // keep test output quiet
+	if err != nil {
+		t.Fatalf("NewLogger: %v", err)
+	}
+	r := NewReporter(logger, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx, time.Hour)
+
+	r.Submit(Event{File: "a.json", Stage: "clean", PostsKept: 1, CommentsKept: 3, BytesIn: 10, BytesOut: 5})
+	r.Submit(Event{File: "b.json", Stage: "clean", SkippedReason: "empty_comments", BytesIn: 4})
+	r.Close()
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	r.MetricsHandler().ServeHTTP(rec, req)
+
+	body := rec.Body.String()
+	for _, want := range []string{
+		"stego_cleaner_files_done_total 2",
+		"stego_cleaner_posts_kept_total 1",
+		"stego_cleaner_comments_kept_total 3",
+		"stego_cleaner_bytes_in_total 14",
+		"stego_cleaner_bytes_out_total 5",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics output missing %q, got:\n%s", want, body)
+		}
+	}
+}